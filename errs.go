@@ -1,18 +1,70 @@
 package errs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime"
+	"strings"
+)
+
+// Number of stack frames skipped (runtime.Callers skip) by default, so that the
+// first captured frame is the caller of a New*Error constructor called directly.
+const defaultSkip = 4
+
+// Maximum number of stack frames kept per error.
+const maxFrames = 32
+
+// packagePrefix identifies frames belonging to this package itself, so
+// CallerName and CallSite can skip past them even when a constructor is
+// reached through an extra layer of indirection inside the package.
+const packagePrefix = "github.com/LemontechSA/common-go-errors."
+
+// Frame describes a single entry of a captured call stack.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Kind identifies the semantic category of an error, independent of any
+// particular transport (HTTP status, gRPC code, ...). It implements `error`
+// so it can be used directly as the target of errors.Is.
+type Kind string
+
+// Returns the Kind as a string so it satisfies the error interface.
+func (k Kind) Error() string {
+	return string(k)
+}
+
+// Well-known error kinds. Callers should match against these instead of
+// comparing HTTP status codes directly.
+const (
+	KindBadRequest          Kind = "bad_request"
+	KindUnauthorized        Kind = "unauthorized"
+	KindPaymentRequired     Kind = "payment_required"
+	KindForbidden           Kind = "forbidden"
+	KindNotFound            Kind = "not_found"
+	KindConflict            Kind = "conflict"
+	KindUnprocessableEntity Kind = "unprocessable_entity"
+	KindTimeout             Kind = "timeout"
+	KindInternal            Kind = "internal"
+	KindNotImplemented      Kind = "not_implemented"
+	KindBadGateway          Kind = "bad_gateway"
+	KindUnavailable         Kind = "unavailable"
 )
 
 // Errors wrapper structure
 type ErrorWrapper struct {
-	Action  string            `json:"action"`  // Human readable action that origin the error.
-	Message string            `json:"message"` // Human readable message for clients.
-	Payload map[string]string `json:"payload"` // Extra information for logs purposes.
-	Code    int               `json:"-"`       // HTTP Status code. `-` is used to skip json marshaling.
-	Err     error             `json:"-"`       // The original error. Same reason as above.
+	Action       string            `json:"action"`  // Human readable action that origin the error.
+	Message      string            `json:"message"` // Human readable message for clients.
+	Payload      map[string]string `json:"payload"` // Extra information for logs purposes.
+	Code         int               `json:"-"`       // HTTP Status code. `-` is used to skip json marshaling.
+	Kind         Kind              `json:"-"`       // Semantic error category, for errors.Is/errors.As matching.
+	Err          error             `json:"-"`       // The original error. Same reason as above.
+	frames       []Frame           // Call stack captured at construction time.
+	includeStack bool              // Whether AsJSONResponse should surface frames.
 }
 
 // Returns Message if Err is nil.
@@ -42,6 +94,157 @@ func (err ErrorWrapper) Dig() ErrorWrapper {
 	return err
 }
 
+// Is reports whether target is the Kind of this error, so that
+// errors.Is(err, errs.KindNotFound) works regardless of the inner cause.
+func (err ErrorWrapper) Is(target error) bool {
+	k, ok := target.(Kind)
+	if !ok {
+		return false
+	}
+
+	return err.Kind != "" && err.Kind == k
+}
+
+// KindOf walks the error chain via errors.As and returns the Kind carried by
+// the first ErrorWrapper found, or the empty Kind if none is found.
+func KindOf(err error) Kind {
+	var ew ErrorWrapper
+
+	if errors.As(err, &ew) {
+		return ew.Kind
+	}
+
+	return ""
+}
+
+// HTTPStatus maps a Kind to its corresponding HTTP status code.
+func HTTPStatus(kind Kind) int {
+	switch kind {
+	case KindBadRequest:
+		return http.StatusBadRequest
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindPaymentRequired:
+		return http.StatusPaymentRequired
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnprocessableEntity:
+		return http.StatusUnprocessableEntity
+	case KindTimeout:
+		return http.StatusGatewayTimeout
+	case KindInternal:
+		return http.StatusInternalServerError
+	case KindNotImplemented:
+		return http.StatusNotImplemented
+	case KindBadGateway:
+		return http.StatusBadGateway
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Frames returns the call stack captured when the error was constructed.
+func (err ErrorWrapper) Frames() []Frame {
+	return err.frames
+}
+
+// CallerName returns the function name of the first non-package frame, i.e.
+// the function that actually triggered the error, so logs can attribute
+// failures without digging through the whole stack.
+func (err ErrorWrapper) CallerName() string {
+	f, ok := firstNonPackageFrame(err.frames)
+	if !ok {
+		return ""
+	}
+
+	return f.Function
+}
+
+// CallSite returns the file:line of the first non-package frame.
+func (err ErrorWrapper) CallSite() string {
+	f, ok := firstNonPackageFrame(err.frames)
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// firstNonPackageFrame returns the first frame that isn't part of this
+// package, falling back to the first frame available if every captured
+// frame happens to be internal.
+func firstNonPackageFrame(frames []Frame) (Frame, bool) {
+	for _, f := range frames {
+		if !strings.HasPrefix(f.Function, packagePrefix) {
+			return f, true
+		}
+	}
+
+	if len(frames) > 0 {
+		return frames[0], true
+	}
+
+	return Frame{}, false
+}
+
+// WithStack returns a copy of the error that will include its captured stack
+// frames when rendered through AsJSONResponse. Intended for debug builds.
+func (err ErrorWrapper) WithStack() ErrorWrapper {
+	err.includeStack = true
+
+	return err
+}
+
+// captureFrames walks the current goroutine's stack, skipping the first
+// `skip` frames, and returns it as a slice of Frame.
+func captureFrames(skip int) []Frame {
+	pcs := make([]uintptr, maxFrames)
+
+	n := runtime.Callers(skip, pcs)
+	if n == 0 {
+		return nil
+	}
+
+	framesIter := runtime.CallersFrames(pcs[:n])
+	frames := make([]Frame, 0, n)
+
+	for {
+		frame, more := framesIter.Next()
+
+		frames = append(frames, Frame{
+			Function: frame.Function,
+			File:     frame.File,
+			Line:     frame.Line,
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return frames
+}
+
+// framesFor returns the stack to attach to a newly constructed ErrorWrapper.
+// When the wrapped error already carries frames (e.g. it is itself an
+// ErrorWrapper obtained via errors.As), the innermost stack is preserved
+// instead of being overwritten by the new construction site.
+func framesFor(err error, skip int) []Frame {
+	var inner ErrorWrapper
+
+	if errors.As(err, &inner) && len(inner.frames) > 0 {
+		return inner.frames
+	}
+
+	return captureFrames(skip)
+}
+
 // Add a value to payload - if the key already exists the value will be overrite
 func (err *ErrorWrapper) AddPayloadValue(key string, value string) {
 	if err.Payload == nil {
@@ -62,16 +265,40 @@ func (err *ErrorWrapper) AddPayloadValues(values map[string]string) {
 	}
 }
 
-// Returns the values of action and message as json
+// Returns the values of action and message as json. When the error was
+// built with WithStack(), a "stack" key with the captured frames (as a JSON
+// array) is included too.
 func (err ErrorWrapper) AsJSONResponse() map[string]string {
-	return map[string]string{
+	resp := map[string]string{
 		"action":  err.Action,
 		"message": err.Message,
 	}
+
+	if err.includeStack && len(err.frames) > 0 {
+		if stack, marshalErr := json.Marshal(err.frames); marshalErr == nil {
+			resp["stack"] = string(stack)
+		}
+	}
+
+	return resp
 }
 
 // Returns the inner ErrorWrapper or a generic one
 func DecodeError(err error) ErrorWrapper {
+	// Checked before ErrorWrapper: errors.As walks MultiError's
+	// Unwrap() []error depth-first, so an ErrorWrapper check placed first
+	// would match the first child instead of the aggregate.
+	var me *MultiError
+	if errors.As(err, &me) {
+		return ErrorWrapper{
+			Action:  "multi_error",
+			Message: me.Error(),
+			Code:    me.Code(),
+			Kind:    KindInternal,
+			Err:     me,
+		}
+	}
+
 	var ew ErrorWrapper
 
 	if errors.As(err, &ew) {
@@ -82,6 +309,7 @@ func DecodeError(err error) ErrorWrapper {
 		Action:  "generic",
 		Message: err.Error(),
 		Code:    http.StatusInternalServerError,
+		Kind:    KindInternal,
 		Err:     err,
 		Payload: nil,
 	}
@@ -108,6 +336,38 @@ func NewErrorWrapper(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
+	return ew
+}
+
+// NewErrorWrapperWithSkip behaves like NewErrorWrapper but adds `skip` extra
+// frames on top of the default depth before capturing the stack. Wrapper
+// helpers built on top of this package should use it so their own frames
+// don't pollute the reported call site.
+func NewErrorWrapperWithSkip(
+	action string,
+	message string,
+	code int,
+	err error,
+	payload map[string]string,
+	skip int,
+) error {
+	ew := ErrorWrapper{
+		Action:  action,
+		Message: message,
+		Code:    code,
+		Err:     err,
+		Payload: payload,
+	}
+
+	ew.AddPayloadValues(map[string]string{
+		"code":          fmt.Sprint(code),
+		"human_message": message,
+	})
+
+	ew.frames = framesFor(err, defaultSkip+skip)
+
 	return ew
 }
 
@@ -123,6 +383,7 @@ func NewBadRequestError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindBadRequest,
 		Err:     err,
 		Payload: payload,
 	}
@@ -132,6 +393,8 @@ func NewBadRequestError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -147,6 +410,7 @@ func NewUnauthorizedError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindUnauthorized,
 		Err:     err,
 		Payload: payload,
 	}
@@ -156,6 +420,8 @@ func NewUnauthorizedError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -171,6 +437,7 @@ func NewPaymentRequiredError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindPaymentRequired,
 		Err:     err,
 		Payload: payload,
 	}
@@ -180,6 +447,8 @@ func NewPaymentRequiredError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -195,6 +464,7 @@ func NewForbiddenError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindForbidden,
 		Err:     err,
 		Payload: payload,
 	}
@@ -204,6 +474,8 @@ func NewForbiddenError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -219,6 +491,7 @@ func NewNotFoundError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindNotFound,
 		Err:     err,
 		Payload: payload,
 	}
@@ -228,6 +501,8 @@ func NewNotFoundError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -243,6 +518,7 @@ func NewUnprocessableEntityError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindUnprocessableEntity,
 		Err:     err,
 		Payload: payload,
 	}
@@ -252,6 +528,8 @@ func NewUnprocessableEntityError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -267,6 +545,7 @@ func NewInternalServerError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindInternal,
 		Err:     err,
 		Payload: payload,
 	}
@@ -276,6 +555,8 @@ func NewInternalServerError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -291,6 +572,7 @@ func NewNotImplementedError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindNotImplemented,
 		Err:     err,
 		Payload: payload,
 	}
@@ -300,6 +582,8 @@ func NewNotImplementedError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -315,6 +599,7 @@ func NewBadGatewayError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindBadGateway,
 		Err:     err,
 		Payload: payload,
 	}
@@ -324,6 +609,8 @@ func NewBadGatewayError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -339,6 +626,7 @@ func NewServiceUnavailableError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindUnavailable,
 		Err:     err,
 		Payload: payload,
 	}
@@ -348,6 +636,8 @@ func NewServiceUnavailableError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
 
@@ -363,6 +653,7 @@ func NewGatewayTimeoutError(
 		Action:  action,
 		Message: message,
 		Code:    code,
+		Kind:    KindTimeout,
 		Err:     err,
 		Payload: payload,
 	}
@@ -372,5 +663,7 @@ func NewGatewayTimeoutError(
 		"human_message": message,
 	})
 
+	ew.frames = framesFor(err, defaultSkip)
+
 	return ew
 }
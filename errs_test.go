@@ -0,0 +1,176 @@
+package errs_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	errs "github.com/LemontechSA/common-go-errors"
+)
+
+func TestKindIsMatchesAcrossWrapping(t *testing.T) {
+	err := errs.NewNotFoundError("find_user", "user not found", nil, nil)
+	wrapped := errs.NewInternalServerError("load_profile", "failed to load profile", err, nil)
+
+	if !errors.Is(err, errs.KindNotFound) {
+		t.Fatal("expected errors.Is to match KindNotFound on the originating error")
+	}
+
+	// errors.Is walks the whole chain, so the wrapper also matches the
+	// Kind of the cause it wraps...
+	if !errors.Is(wrapped, errs.KindNotFound) {
+		t.Fatal("expected errors.Is to match KindNotFound through the wrapping error's cause")
+	}
+
+	// ...as well as its own, more specific Kind.
+	if !errors.Is(wrapped, errs.KindInternal) {
+		t.Fatal("expected errors.Is to match KindInternal on the wrapping error itself")
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	err := errs.NewForbiddenError("delete_account", "not allowed", nil, nil)
+
+	if got := errs.KindOf(err); got != errs.KindForbidden {
+		t.Fatalf("KindOf() = %q, want %q", got, errs.KindForbidden)
+	}
+
+	if got := errs.KindOf(errors.New("plain error")); got != "" {
+		t.Fatalf("KindOf() on a plain error = %q, want empty", got)
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	cases := map[errs.Kind]int{
+		errs.KindBadRequest:   http.StatusBadRequest,
+		errs.KindNotFound:     http.StatusNotFound,
+		errs.KindUnauthorized: http.StatusUnauthorized,
+	}
+
+	for kind, want := range cases {
+		if got := errs.HTTPStatus(kind); got != want {
+			t.Errorf("HTTPStatus(%q) = %d, want %d", kind, got, want)
+		}
+	}
+}
+
+func TestFramesCaptureCaller(t *testing.T) {
+	err := errs.NewBadRequestError("validate", "bad input", nil, nil)
+	ew := errs.DecodeError(err)
+
+	if len(ew.Frames()) == 0 {
+		t.Fatal("expected at least one captured frame")
+	}
+
+	if got := ew.CallerName(); got != "github.com/LemontechSA/common-go-errors_test.TestFramesCaptureCaller" {
+		t.Errorf("CallerName() = %q, want the test function", got)
+	}
+}
+
+func TestFramesPreserveInnermostStack(t *testing.T) {
+	inner := errs.NewNotFoundError("find_user", "user not found", nil, nil)
+	outer := errs.NewInternalServerError("load_profile", "failed", inner, nil)
+
+	innerFrames := errs.DecodeError(inner).Frames()
+	outerFrames := errs.DecodeError(outer).Frames()
+
+	if len(innerFrames) == 0 || len(outerFrames) == 0 {
+		t.Fatal("expected both errors to carry frames")
+	}
+
+	if innerFrames[0] != outerFrames[0] {
+		t.Errorf("expected outer error to preserve inner frames, got %+v vs %+v", outerFrames[0], innerFrames[0])
+	}
+}
+
+func TestMultiErrorCodeIsHighestByDefault(t *testing.T) {
+	err := errs.NewMultiError(
+		errs.NewBadRequestError("field_a", "required", nil, nil),
+		errs.NewInternalServerError("field_b", "boom", nil, nil),
+	)
+
+	ew := errs.DecodeError(err)
+
+	if ew.Code != http.StatusInternalServerError {
+		t.Fatalf("DecodeError(multi).Code = %d, want %d (highest child code)", ew.Code, http.StatusInternalServerError)
+	}
+
+	if ew.Action != "multi_error" {
+		t.Errorf("DecodeError(multi).Action = %q, want %q", ew.Action, "multi_error")
+	}
+}
+
+func TestMultiErrorAsJSONResponseIncludesPerChildPayload(t *testing.T) {
+	err := errs.NewMultiError(
+		errs.NewBadRequestError("field_a", "required", nil, map[string]string{"field": "a"}),
+		errs.NewInternalServerError("field_b", "boom", nil, nil),
+	)
+
+	var me *errs.MultiError
+	if !errors.As(err, &me) {
+		t.Fatal("expected a *MultiError")
+	}
+
+	resp := me.AsJSONResponse()
+
+	items, ok := resp["errors"].([]map[string]interface{})
+	if !ok || len(items) != 2 {
+		t.Fatalf("resp[\"errors\"] = %#v, want a 2-element slice", resp["errors"])
+	}
+
+	payload, ok := items[0]["payload"].(map[string]string)
+	if !ok || payload["field"] != "a" {
+		t.Errorf("items[0][\"payload\"] = %#v, want it to include {field: a}", items[0]["payload"])
+	}
+
+	payload, ok = items[1]["payload"].(map[string]string)
+	if !ok || payload["code"] != "500" {
+		t.Errorf("items[1][\"payload\"] = %#v, want it to include {code: 500}", items[1]["payload"])
+	}
+}
+
+func TestAsJSONResponseDispatchesMultiAndSingle(t *testing.T) {
+	single := errs.NewNotFoundError("find_user", "user not found", nil, nil)
+
+	resp := errs.AsJSONResponse(single)
+	if resp["action"] != "find_user" || resp["message"] != "user not found" {
+		t.Fatalf("AsJSONResponse(single) = %#v", resp)
+	}
+
+	multi := errs.NewMultiError(single, errs.NewBadRequestError("field_a", "required", nil, nil))
+
+	resp = errs.AsJSONResponse(multi)
+	if _, ok := resp["errors"]; !ok {
+		t.Fatalf("AsJSONResponse(multi) = %#v, want an \"errors\" key", resp)
+	}
+}
+
+func TestMultiErrorCustomReducer(t *testing.T) {
+	me := &errs.MultiError{
+		Errors: []error{
+			errs.NewBadRequestError("field_a", "required", nil, nil),
+			errs.NewInternalServerError("field_b", "boom", nil, nil),
+		},
+		Reducer: func(codes []int) int { return codes[0] },
+	}
+
+	if got := me.Code(); got != http.StatusBadRequest {
+		t.Fatalf("Code() with custom reducer = %d, want %d", got, http.StatusBadRequest)
+	}
+}
+
+func TestAppendAccumulates(t *testing.T) {
+	var err error
+
+	err = errs.Append(err, errs.NewBadRequestError("field_a", "required", nil, nil))
+	err = errs.Append(err, errs.NewBadRequestError("field_b", "required", nil, nil))
+
+	var me *errs.MultiError
+	if !errors.As(err, &me) {
+		t.Fatal("expected Append to produce a *MultiError")
+	}
+
+	if len(me.Errors) != 2 {
+		t.Fatalf("len(me.Errors) = %d, want 2", len(me.Errors))
+	}
+}
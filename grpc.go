@@ -0,0 +1,155 @@
+package errs
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ToGRPCStatus translates err (typically an ErrorWrapper) into a gRPC
+// status, mapping its HTTP Code to the closest codes.Code and attaching
+// Action, Message and Payload as an errdetails.ErrorInfo detail.
+func ToGRPCStatus(err error) *status.Status {
+	ew := DecodeError(err)
+
+	st := status.New(grpcCodeFromHTTP(ew.Code), ew.Message)
+
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   ew.Action,
+		Domain:   "common-go-errors",
+		Metadata: ew.Payload,
+	})
+	if detailErr != nil {
+		return st
+	}
+
+	return withDetails
+}
+
+// FromGRPCStatus converts a gRPC status error back into an ErrorWrapper,
+// recovering Action and Payload from the ErrorInfo detail when the server
+// attached one via ToGRPCStatus.
+func FromGRPCStatus(err error) ErrorWrapper {
+	st, ok := status.FromError(err)
+	if !ok {
+		return DecodeError(err)
+	}
+
+	ew := ErrorWrapper{
+		Action:  "grpc",
+		Message: st.Message(),
+		Code:    httpStatusFromGRPC(st.Code()),
+		Err:     err,
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+
+		ew.Action = info.GetReason()
+		ew.Payload = info.GetMetadata()
+	}
+
+	return ew
+}
+
+// UnaryServerInterceptor converts any error returned by a unary handler into
+// a proper gRPC status, so ErrorWrapper-producing services expose the same
+// semantics over gRPC as they do over REST.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, ToGRPCStatus(err).Err()
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := handler(srv, ss); err != nil {
+			return ToGRPCStatus(err).Err()
+		}
+
+		return nil
+	}
+}
+
+// grpcCodeFromHTTP maps an HTTP status code to the closest gRPC code.
+func grpcCodeFromHTTP(code int) codes.Code {
+	switch code {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusUnprocessableEntity:
+		return codes.FailedPrecondition
+	case 499: // Client Closed Request, nginx convention for a cancelled request
+		return codes.Canceled
+	case http.StatusInternalServerError:
+		return codes.Internal
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	case http.StatusGatewayTimeout:
+		return codes.DeadlineExceeded
+	default:
+		return codes.Unknown
+	}
+}
+
+// httpStatusFromGRPC is the inverse of grpcCodeFromHTTP, used when decoding
+// a status received from a gRPC peer back into an ErrorWrapper.
+func httpStatusFromGRPC(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists:
+		return http.StatusConflict
+	case codes.FailedPrecondition:
+		return http.StatusUnprocessableEntity
+	case codes.Canceled:
+		return 499
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
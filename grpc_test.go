@@ -0,0 +1,55 @@
+package errs_test
+
+import (
+	"net/http"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	errs "github.com/LemontechSA/common-go-errors"
+)
+
+func TestToGRPCStatusMapsCodeAndDetails(t *testing.T) {
+	err := errs.NewNotFoundError("find_user", "user not found", nil, map[string]string{"user_id": "42"})
+
+	st := errs.ToGRPCStatus(err)
+
+	if st.Code() != codes.NotFound {
+		t.Fatalf("st.Code() = %v, want %v", st.Code(), codes.NotFound)
+	}
+
+	if st.Message() != "user not found" {
+		t.Fatalf("st.Message() = %q, want %q", st.Message(), "user not found")
+	}
+}
+
+func TestFromGRPCStatusRoundTrips(t *testing.T) {
+	original := errs.NewNotFoundError("find_user", "user not found", nil, map[string]string{"user_id": "42"})
+
+	st := errs.ToGRPCStatus(original)
+
+	recovered := errs.FromGRPCStatus(st.Err())
+
+	if recovered.Code != http.StatusNotFound {
+		t.Errorf("recovered.Code = %d, want %d", recovered.Code, http.StatusNotFound)
+	}
+
+	if recovered.Action != "find_user" {
+		t.Errorf("recovered.Action = %q, want %q", recovered.Action, "find_user")
+	}
+
+	if recovered.Payload["user_id"] != "42" {
+		t.Errorf("recovered.Payload[user_id] = %q, want %q", recovered.Payload["user_id"], "42")
+	}
+}
+
+func TestFromGRPCStatusOnPlainError(t *testing.T) {
+	plain := status.Error(codes.Unavailable, "downstream unavailable")
+
+	recovered := errs.FromGRPCStatus(plain)
+
+	if recovered.Code != http.StatusServiceUnavailable {
+		t.Fatalf("recovered.Code = %d, want %d", recovered.Code, http.StatusServiceUnavailable)
+	}
+}
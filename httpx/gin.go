@@ -0,0 +1,29 @@
+package httpx
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	errs "github.com/LemontechSA/common-go-errors"
+)
+
+// Gin recovers from panics in a Gin handler chain and reports them through
+// WriteError, mirroring Middleware for consumers on top of gin.Engine.
+func Gin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rec)
+				}
+
+				WriteError(c.Writer, c.Request, errs.NewInternalServerError("panic_recovery", "internal server error", err, nil))
+				c.Abort()
+			}
+		}()
+
+		c.Next()
+	}
+}
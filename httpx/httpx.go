@@ -0,0 +1,107 @@
+// Package httpx wires ErrorWrapper into the net/http world: a helper to
+// write an error as a JSON response, and a recovery middleware built on top
+// of it.
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+
+	errs "github.com/LemontechSA/common-go-errors"
+)
+
+// DebugEnvVar, when set to a truthy value ("1" or "true"), makes WriteError
+// include Payload and stack frames in the response body.
+const DebugEnvVar = "ERRS_DEBUG"
+
+// DebugIDHeader is the response header WriteError uses to echo the
+// request-scoped debug id it generated.
+const DebugIDHeader = "X-Debug-Id"
+
+type errorResponse struct {
+	Action  string            `json:"action"`
+	Message string            `json:"message"`
+	Payload map[string]string `json:"payload,omitempty"`
+	Stack   []errs.Frame      `json:"stack,omitempty"`
+}
+
+func isDebug() bool {
+	v := strings.ToLower(os.Getenv(DebugEnvVar))
+
+	return v == "1" || v == "true"
+}
+
+// WriteError decodes err into an ErrorWrapper, writes its Code as the HTTP
+// status and marshals a JSON body: a flat action/message object, or, for a
+// MultiError, the errors: [...] array of errs.AsJSONResponse. A
+// DebugID is generated per call and echoed back via the X-Debug-Id header,
+// and logged alongside the error through slog, so a client-visible error
+// can always be correlated to the server log line that produced it - even
+// outside debug mode. In debug mode (see DebugEnvVar) the flat body also
+// includes the payload (with the debug id added) and the captured stack
+// frames. The MultiError body does not depend on debug mode: it always
+// carries each child's payload, and never the debug id or stack frames.
+func WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	debugID := uuid.NewString()
+	ew := errs.DecodeError(err)
+
+	slog.Error("request failed", "err", ew, "debug_id", debugID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(DebugIDHeader, debugID)
+	w.WriteHeader(ew.Code)
+
+	if _, isMulti := ew.Err.(*errs.MultiError); isMulti {
+		_ = json.NewEncoder(w).Encode(errs.AsJSONResponse(err))
+
+		return
+	}
+
+	body := errorResponse{
+		Action:  ew.Action,
+		Message: ew.Message,
+	}
+
+	if isDebug() {
+		// Copy the payload before injecting the debug id: ew.Payload is the
+		// same map held by the caller's original ErrorWrapper, and mutating
+		// it in place would leak this response's debug id back into that
+		// error.
+		payload := make(map[string]string, len(ew.Payload)+1)
+		for k, v := range ew.Payload {
+			payload[k] = v
+		}
+		payload["debug_id"] = debugID
+
+		body.Payload = payload
+		body.Stack = ew.Frames()
+	}
+
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Middleware recovers from panics raised downstream and reports them through
+// WriteError as an internal server error, so a single handler panic doesn't
+// take down the server without returning the library's structured response.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				err, ok := rec.(error)
+				if !ok {
+					err = fmt.Errorf("%v", rec)
+				}
+
+				WriteError(w, r, errs.NewInternalServerError("panic_recovery", "internal server error", err, nil))
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
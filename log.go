@@ -0,0 +1,36 @@
+package errs
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// LogValue implements slog.LogValuer so that slog.Error("...", "err", ew)
+// produces a well-formed structured log group without a custom serializer.
+func (err ErrorWrapper) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("action", err.Action),
+		slog.String("message", err.Message),
+		slog.Int("code", err.Code),
+	}
+
+	if len(err.Payload) > 0 {
+		payload := make([]slog.Attr, 0, len(err.Payload))
+		for k, v := range err.Payload {
+			payload = append(payload, slog.String(k, v))
+		}
+
+		attrs = append(attrs, slog.Attr{Key: "payload", Value: slog.GroupValue(payload...)})
+	}
+
+	if len(err.frames) > 0 {
+		stack := make([]string, len(err.frames))
+		for i, f := range err.frames {
+			stack[i] = fmt.Sprintf("%s (%s:%d)", f.Function, f.File, f.Line)
+		}
+
+		attrs = append(attrs, slog.Any("stack", stack))
+	}
+
+	return slog.GroupValue(attrs...)
+}
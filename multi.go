@@ -0,0 +1,140 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// MultiError aggregates several errors produced while handling a single
+// request (e.g. validating many fields at once), while still satisfying the
+// error interface so it flows through DecodeError and friends like any
+// other error.
+type MultiError struct {
+	Errors []error
+
+	// Reducer, when set, computes the Code reported for the aggregate from
+	// the codes of its children. Defaults to the highest code among them.
+	Reducer func(codes []int) int
+}
+
+// NewMultiError builds a MultiError out of the given errors.
+func NewMultiError(errs ...error) error {
+	return &MultiError{Errors: errs}
+}
+
+// Append adds err to dst, turning dst into a *MultiError as needed. It
+// mirrors the ergonomics of hashicorp/go-multierror so validators can
+// accumulate errors without caring whether dst is nil, a plain error or
+// already a *MultiError.
+func Append(dst error, err error) error {
+	if err == nil {
+		return dst
+	}
+
+	if dst == nil {
+		return NewMultiError(err)
+	}
+
+	var me *MultiError
+	if errors.As(dst, &me) {
+		me.Errors = append(me.Errors, err)
+
+		return me
+	}
+
+	return NewMultiError(dst, err)
+}
+
+// Error implements the error interface.
+func (m *MultiError) Error() string {
+	if m == nil || len(m.Errors) == 0 {
+		return ""
+	}
+
+	if len(m.Errors) == 1 {
+		return m.Errors[0].Error()
+	}
+
+	points := make([]string, len(m.Errors))
+	for i, err := range m.Errors {
+		points[i] = fmt.Sprintf("* %s", err.Error())
+	}
+
+	return fmt.Sprintf("%d errors occurred:\n\t%s\n", len(m.Errors), strings.Join(points, "\n\t"))
+}
+
+// Unwrap exposes the aggregated errors so errors.Is and errors.As can walk
+// into any of them.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors
+}
+
+// Code reports the HTTP status for the aggregate: the highest code among
+// its children, unless Reducer is set.
+func (m *MultiError) Code() int {
+	if len(m.Errors) == 0 {
+		return http.StatusInternalServerError
+	}
+
+	codes := make([]int, len(m.Errors))
+	for i, err := range m.Errors {
+		codes[i] = DecodeError(err).Code
+	}
+
+	if m.Reducer != nil {
+		return m.Reducer(codes)
+	}
+
+	highest := codes[0]
+	for _, c := range codes[1:] {
+		if c > highest {
+			highest = c
+		}
+	}
+
+	return highest
+}
+
+// AsJSONResponse renders every child error's action, message and payload, so
+// clients see each underlying failure individually instead of a single
+// flattened one.
+func (m *MultiError) AsJSONResponse() map[string]interface{} {
+	items := make([]map[string]interface{}, len(m.Errors))
+	for i, err := range m.Errors {
+		ew := DecodeError(err)
+
+		item := map[string]interface{}{
+			"action":  ew.Action,
+			"message": ew.Message,
+		}
+
+		if len(ew.Payload) > 0 {
+			item["payload"] = ew.Payload
+		}
+
+		items[i] = item
+	}
+
+	return map[string]interface{}{"errors": items}
+}
+
+// AsJSONResponse renders err as the JSON body API handlers should return: a
+// flat {action, message} object for a single error, or the {errors: [...]}
+// array for a *MultiError. Prefer this over calling
+// DecodeError(err).AsJSONResponse() directly, since that method only ever
+// renders the flat, single-error shape.
+func AsJSONResponse(err error) map[string]interface{} {
+	var me *MultiError
+	if errors.As(err, &me) {
+		return me.AsJSONResponse()
+	}
+
+	resp := make(map[string]interface{}, 2)
+	for k, v := range DecodeError(err).AsJSONResponse() {
+		resp[k] = v
+	}
+
+	return resp
+}
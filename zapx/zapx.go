@@ -0,0 +1,63 @@
+// Package zapx adapts ErrorWrapper to zap's structured fields. It is kept
+// out of the root package so consumers that only need errs aren't forced to
+// pull in zap as a dependency.
+package zapx
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	errs "github.com/LemontechSA/common-go-errors"
+)
+
+// ZapFields flattens err into zap fields: action, message, code, one field
+// per payload entry, and err.cause.N for every error further down the
+// causal chain, preserving order. Both the usual Unwrap() error and the
+// multi-error Unwrap() []error (e.g. errs.MultiError) shapes are followed.
+func ZapFields(err error) []zap.Field {
+	ew := errs.DecodeError(err)
+
+	fields := []zap.Field{
+		zap.String("action", ew.Action),
+		zap.String("message", ew.Message),
+		zap.Int("code", ew.Code),
+	}
+
+	for k, v := range ew.Payload {
+		fields = append(fields, zap.String("payload."+k, v))
+	}
+
+	i := 0
+
+	return walkCauses(fields, err, &i)
+}
+
+// walkCauses depth-first walks err's causal chain, appending one
+// err.cause.N field per error found, via whichever Unwrap shape err
+// implements.
+func walkCauses(fields []zap.Field, err error, i *int) []zap.Field {
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		cause := u.Unwrap()
+		if cause == nil {
+			return fields
+		}
+
+		fields = append(fields, zap.String(fmt.Sprintf("err.cause.%d", *i), cause.Error()))
+		*i++
+
+		return walkCauses(fields, cause, i)
+	case interface{ Unwrap() []error }:
+		for _, cause := range u.Unwrap() {
+			fields = append(fields, zap.String(fmt.Sprintf("err.cause.%d", *i), cause.Error()))
+			*i++
+
+			fields = walkCauses(fields, cause, i)
+		}
+
+		return fields
+	default:
+		return fields
+	}
+}